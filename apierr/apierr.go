@@ -0,0 +1,61 @@
+// Package apierr defines the uniform JSON error envelope returned by every
+// handler, so clients always get the same shape regardless of which
+// endpoint failed.
+package apierr
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ksx2/trpo3_code_style/validation"
+)
+
+// Error codes used in the envelope's "code" field.
+const (
+	CodeValidation   = "VALIDATION"
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeInternal     = "INTERNAL"
+)
+
+// APIError is the uniform error envelope. Errors is populated only for
+// CodeValidation; other codes carry a single Message.
+type APIError struct {
+	Status  int                     `json:"-"`
+	Code    string                  `json:"code"`
+	Message string                  `json:"message,omitempty"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// New builds an APIError carrying a single message.
+func New(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// FromValidation builds a 422 APIError from a validation.Errors value.
+func FromValidation(errs validation.Errors) *APIError {
+	return &APIError{
+		Status: http.StatusUnprocessableEntity,
+		Code:   CodeValidation,
+		Errors: errs,
+	}
+}
+
+// WriteError writes err to w as the uniform JSON envelope. Any error that
+// is not already an *APIError is reported as an opaque 500 so internal
+// details never leak to the client.
+func WriteError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		apiErr = New(http.StatusInternalServerError, CodeInternal, "Internal server error")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Status)
+	json.NewEncoder(w).Encode(apiErr)
+}
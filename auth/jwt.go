@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AccessTokenTTL is how long an issued access token remains valid.
+const AccessTokenTTL = 15 * time.Minute
+
+// ErrNoSigningSecret is returned when JWT_SIGNING_SECRET is not configured.
+var ErrNoSigningSecret = errors.New("auth: JWT_SIGNING_SECRET is not set")
+
+// Claims is the JWT payload issued for an authenticated user.
+type Claims struct {
+	UserID int `json:"uid"`
+	jwt.RegisteredClaims
+}
+
+func signingSecret() ([]byte, error) {
+	secret := os.Getenv("JWT_SIGNING_SECRET")
+	if secret == "" {
+		return nil, ErrNoSigningSecret
+	}
+	return []byte(secret), nil
+}
+
+// IssueAccessToken signs a short-lived HS256 access token for userID.
+func IssueAccessToken(userID int) (string, error) {
+	secret, err := signingSecret()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(secret)
+}
+
+// ParseAccessToken validates tokenString and returns its claims.
+func ParseAccessToken(tokenString string) (*Claims, error) {
+	secret, err := signingSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
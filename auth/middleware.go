@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"ksx2/trpo3_code_style/apierr"
+)
+
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// Middleware validates the bearer access token on incoming requests and
+// populates the authenticated user id into the request context.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			apierr.WriteError(w, apierr.New(http.StatusUnauthorized, apierr.CodeUnauthorized, "Missing bearer token"))
+			return
+		}
+
+		claims, err := ParseAccessToken(token)
+		if err != nil {
+			apierr.WriteError(w, apierr.New(http.StatusUnauthorized, apierr.CodeUnauthorized, "Invalid or expired token"))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, claims.UserID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// UserIDFromContext returns the authenticated user id stored by Middleware,
+// and false if the context carries none.
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(userIDContextKey).(int)
+	return id, ok
+}
@@ -0,0 +1,61 @@
+// Package auth provides password hashing and JWT issuance for the user service.
+package auth
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultCost is used when no cost is configured explicitly.
+const DefaultCost = bcrypt.DefaultCost
+
+// ErrMismatchedPassword is returned by VerifyPassword when the supplied
+// password does not match the stored hash.
+var ErrMismatchedPassword = errors.New("auth: password does not match")
+
+// Hasher hashes and verifies passwords using bcrypt with an optional
+// server-side pepper mixed in before hashing.
+type Hasher struct {
+	cost   int
+	pepper string
+}
+
+// NewHasher builds a Hasher with the given bcrypt cost. If cost is 0,
+// DefaultCost is used. The pepper is read from the AUTH_PASSWORD_PEPPER
+// environment variable; an empty pepper is valid and simply disables it.
+func NewHasher(cost int) *Hasher {
+	if cost == 0 {
+		cost = DefaultCost
+	}
+	return &Hasher{
+		cost:   cost,
+		pepper: os.Getenv("AUTH_PASSWORD_PEPPER"),
+	}
+}
+
+func (h *Hasher) peppered(password string) []byte {
+	return []byte(password + h.pepper)
+}
+
+// HashPassword returns a bcrypt hash of password, with the server-side
+// pepper mixed in, suitable for storage in the users.password_hash column.
+func (h *Hasher) HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword(h.peppered(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// VerifyPassword reports whether password matches the given bcrypt hash.
+// It returns ErrMismatchedPassword on mismatch and a non-nil error for any
+// other failure (e.g. a malformed hash).
+func (h *Hasher) VerifyPassword(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), h.peppered(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatchedPassword
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// RefreshTokenTTL is how long a refresh token remains valid after issuance.
+const RefreshTokenTTL = 30 * 24 * 60 * 60 // seconds, i.e. 30 days
+
+// NewRefreshToken generates a random refresh token along with the digest
+// that should be persisted to the sessions table. Only digest is ever
+// stored; token is returned to the client once and cannot be recovered
+// from digest.
+func NewRefreshToken() (token string, digest string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(raw)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the SHA-256 digest of a refresh token, as stored
+// in sessions.token_hash.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
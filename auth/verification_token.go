@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// VerificationTokenTTL is how long an email verification link remains
+// valid after issuance.
+const VerificationTokenTTL = 24 * 60 * 60 // seconds, i.e. 24h
+
+// NewVerificationToken generates a random email verification token along
+// with the digest that should be persisted to verification_tokens. Only
+// digest is ever stored; token is emailed to the user once and cannot be
+// recovered from digest.
+func NewVerificationToken() (token string, digest string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(raw)
+	return token, HashVerificationToken(token), nil
+}
+
+// HashVerificationToken returns the SHA-256 digest of a verification
+// token, as stored in verification_tokens.token_hash.
+func HashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
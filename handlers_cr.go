@@ -1,143 +1,404 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
-	"regexp"
-	"strings"
+	"os"
+	"strconv"
 	"time"
+
+	"ksx2/trpo3_code_style/apierr"
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/mail"
+	"ksx2/trpo3_code_style/models"
+	"ksx2/trpo3_code_style/ratelimit"
+	"ksx2/trpo3_code_style/repository"
+	"ksx2/trpo3_code_style/service"
+	"ksx2/trpo3_code_style/validation"
+
+	"github.com/go-chi/chi/v5"
 )
 
+// userService is the slice of UserService that UserHandler depends on. It's
+// declared here, at the consumer, so handler tests can swap in a fake
+// without importing the concrete service type.
+type userService interface {
+	CreateUser(ctx context.Context, email, password, name string, age int) (*models.User, error)
+	GetUser(ctx context.Context, id int) (*models.User, error)
+	Authenticate(ctx context.Context, email, password string) (*models.User, error)
+	ListUsers(ctx context.Context, params repository.ListParams) (*repository.ListResult, error)
+	UpdateUser(ctx context.Context, id int, name *string, age *int) (*models.User, error)
+	DeleteUser(ctx context.Context, id int) error
+}
+
+// verificationService is the slice of VerificationService that
+// UserHandler depends on.
+type verificationService interface {
+	SendVerificationEmail(ctx context.Context, userID int) error
+	VerifyEmail(ctx context.Context, token string) error
+}
+
 type UserHandler struct {
-	db *sql.DB
+	service       userService
+	verification  verificationService
+	loginAttempts ratelimit.LoginAttemptStore
+	sessions      repository.SessionRepo
 }
 
+// NewUserHandler builds a UserHandler backed by a Postgres-backed
+// UserService, VerificationService and session store, all sharing db.
 func NewUserHandler(db *sql.DB) *UserHandler {
-	return &UserHandler{db: db}
+	repo := repository.NewPostgresUserRepo(db)
+	tokens := repository.NewPostgresVerificationTokenRepo(db)
+	svc := service.NewUserService(repo, auth.NewHasher(auth.DefaultCost))
+	verification := service.NewVerificationService(tokens, repo, mail.NewFromEnv(), os.Getenv("APP_BASE_URL"))
+	h := NewUserHandlerWithService(svc, verification, db)
+	h.loginAttempts = ratelimit.NewPostgresLoginAttemptStore(db)
+	return h
 }
 
-func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var request struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		Name     string `json:"name"`
-		Age      int    `json:"age"`
+// NewUserHandlerWithService builds a UserHandler against arbitrary
+// userService/verificationService implementations, e.g. ones backed by
+// repository fakes in tests. db backs the session store when non-nil;
+// tests that pass nil get an in-memory FakeSessionRepo instead. Login
+// attempt tracking defaults to an in-memory store; override it with
+// WithLoginAttemptStore if the test needs to assert lockout behavior.
+func NewUserHandlerWithService(svc userService, verification verificationService, db *sql.DB) *UserHandler {
+	var sessions repository.SessionRepo
+	if db != nil {
+		sessions = repository.NewPostgresSessionRepo(db)
+	} else {
+		sessions = repository.NewFakeSessionRepo()
+	}
+
+	return &UserHandler{
+		service:       svc,
+		verification:  verification,
+		loginAttempts: ratelimit.NewFakeLoginAttemptStore(),
+		sessions:      sessions,
 	}
-	
+}
+
+// WithLoginAttemptStore overrides the handler's LoginAttemptStore,
+// returning h for chaining.
+func (h *UserHandler) WithLoginAttemptStore(store ratelimit.LoginAttemptStore) *UserHandler {
+	h.loginAttempts = store
+	return h
+}
+
+// CreateUserRequest is the CreateUser request body. Its struct tags drive
+// validation.Struct.
+type CreateUserRequest struct {
+	Email    string `json:"email" validate:"required,email,not_disposable_email"`
+	Password string `json:"password" validate:"required,min=8"`
+	Name     string `json:"name" validate:"required,unicode_name"`
+	Age      int    `json:"age" validate:"gte=18,lte=120"`
+}
+
+// Validate reports every violation in the request at once, rather than
+// stopping at the first one.
+func (r CreateUserRequest) Validate() error {
+	return validation.Struct(r)
+}
+
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var request CreateUserRequest
+
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid JSON"})
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "Invalid JSON"))
 		return
 	}
 
-	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
-	if !emailRegex.MatchString(request.Email) {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid email format"})
+	if err := request.Validate(); err != nil {
+		apierr.WriteError(w, apierr.FromValidation(err.(validation.Errors)))
 		return
 	}
 
-	if len(request.Password) < 8 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Password must be at least 8 characters"})
+	u, err := h.service.CreateUser(r.Context(), request.Email, request.Password, request.Name, request.Age)
+	if errors.Is(err, service.ErrEmailTaken) {
+		apierr.WriteError(w, apierr.New(http.StatusConflict, apierr.CodeConflict, "User with this email already exists"))
 		return
 	}
+	if err != nil {
+		log.Printf("CreateUser error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Failed to create user"))
+		return
+	}
+
+	if err := h.verification.SendVerificationEmail(r.Context(), u.ID); err != nil {
+		// The account was created successfully; a failed verification email
+		// shouldn't fail the request. The user can request a new one via
+		// the resend endpoint.
+		log.Printf("SendVerificationEmail error: %v", err)
+	}
 
-	if strings.TrimSpace(request.Name) == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Name is required"})
+	response := map[string]interface{}{
+		"id":         u.ID,
+		"email":      u.Email,
+		"name":       u.Name,
+		"age":        u.Age,
+		"created_at": u.CreatedAt.Format(time.RFC3339),
+		"message":    "User created successfully, please check your email to verify your account",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	rawID := chi.URLParam(r, "id")
+	if rawID == "" {
+		rawID = r.URL.Query().Get("id")
+	}
+	if rawID == "" {
+		if authedID, ok := auth.UserIDFromContext(r.Context()); ok {
+			rawID = strconv.Itoa(authedID)
+		}
+	}
+	if rawID == "" {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "User ID is required"))
 		return
 	}
 
-	if request.Age < 18 || request.Age > 120 {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Age must be between 18 and 120"})
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "User ID must be numeric"))
 		return
 	}
 
-	var existingID int
-	err := h.db.QueryRow("SELECT id FROM users WHERE email = $1", request.Email).Scan(&existingID)
-	if err != nil && err != sql.ErrNoRows {
-		log.Printf("Database error: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
+	u, err := h.service.GetUser(r.Context(), id)
+	if errors.Is(err, service.ErrNotFound) {
+		apierr.WriteError(w, apierr.New(http.StatusNotFound, apierr.CodeNotFound, "User not found"))
 		return
 	}
-	if err == nil {
-		w.WriteHeader(http.StatusConflict)
-		json.NewEncoder(w).Encode(map[string]string{"error": "User with this email already exists"})
+	if err != nil {
+		log.Printf("GetUser error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
 		return
 	}
 
-	hashedPassword := fmt.Sprintf("hashed_%s", request.Password)
+	response := map[string]interface{}{
+		"id":         u.ID,
+		"email":      u.Email,
+		"name":       u.Name,
+		"age":        u.Age,
+		"created_at": u.CreatedAt.Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UpdateUserRequest is the PATCH /users/{id} request body. Both fields are
+// optional; only the ones present are applied. Name and Age carry the same
+// bounds as CreateUserRequest so a partial update can't put a user into a
+// state creation would have rejected.
+type UpdateUserRequest struct {
+	Name *string `json:"name" validate:"omitempty,unicode_name"`
+	Age  *int    `json:"age" validate:"omitempty,gte=18,lte=120"`
+}
 
-	query := `INSERT INTO users (email, password_hash, name, age, created_at) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	
-	var userID int
-	err = h.db.QueryRow(query, request.Email, hashedPassword, request.Name, request.Age, time.Now()).Scan(&userID)
+// Validate reports every violation in the request at once, rather than
+// stopping at the first one.
+func (r UpdateUserRequest) Validate() error {
+	return validation.Struct(r)
+}
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 	if err != nil {
-		log.Printf("Database error: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create user"})
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "User ID must be numeric"))
+		return
+	}
+
+	if authedID, ok := auth.UserIDFromContext(r.Context()); !ok || authedID != id {
+		apierr.WriteError(w, apierr.New(http.StatusForbidden, apierr.CodeUnauthorized, "You may only modify your own account"))
+		return
+	}
+
+	var request UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "Invalid JSON"))
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		apierr.WriteError(w, apierr.FromValidation(err.(validation.Errors)))
+		return
+	}
+
+	u, err := h.service.UpdateUser(r.Context(), id, request.Name, request.Age)
+	if errors.Is(err, service.ErrNotFound) {
+		apierr.WriteError(w, apierr.New(http.StatusNotFound, apierr.CodeNotFound, "User not found"))
+		return
+	}
+	if err != nil {
+		log.Printf("UpdateUser error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
 		return
 	}
 
 	response := map[string]interface{}{
-		"id":        userID,
-		"email":     request.Email,
-		"name":      request.Name,
-		"age":       request.Age,
-		"created_at": time.Now().Format(time.RFC3339),
-		"message":   "User created successfully",
+		"id":         u.ID,
+		"email":      u.Email,
+		"name":       u.Name,
+		"age":        u.Age,
+		"created_at": u.CreatedAt.Format(time.RFC3339),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
 }
 
-func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("id")
-	if userID == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "User ID is required"})
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "User ID must be numeric"))
 		return
 	}
 
-	var id int
-	var email, name string
-	var age int
-	var createdAt time.Time
-	
-	err := h.db.QueryRow(
-		"SELECT id, email, name, age, created_at FROM users WHERE id = $1",
-		userID,
-	).Scan(&id, &email, &name, &age, &createdAt)
-	
-	if err == sql.ErrNoRows {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]string{"error": "User not found"})
+	if authedID, ok := auth.UserIDFromContext(r.Context()); !ok || authedID != id {
+		apierr.WriteError(w, apierr.New(http.StatusForbidden, apierr.CodeUnauthorized, "You may only delete your own account"))
+		return
+	}
+
+	if err := h.service.DeleteUser(r.Context(), id); errors.Is(err, service.ErrNotFound) {
+		apierr.WriteError(w, apierr.New(http.StatusNotFound, apierr.CodeNotFound, "User not found"))
+		return
+	} else if err != nil {
+		log.Printf("DeleteUser error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LoginUser verifies an email/password pair and issues a JWT access token
+// plus a refresh token backed by a row in the sessions table.
+func (h *UserHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "Invalid JSON"))
+		return
+	}
+
+	u, err := h.service.Authenticate(r.Context(), request.Email, request.Password)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		apierr.WriteError(w, apierr.New(http.StatusUnauthorized, apierr.CodeUnauthorized, "Invalid email or password"))
+		return
+	}
+	if err != nil {
+		log.Printf("Authenticate error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	if !u.IsVerified() && os.Getenv("ALLOW_UNVERIFIED_LOGIN") != "true" {
+		apierr.WriteError(w, apierr.New(http.StatusForbidden, apierr.CodeUnauthorized, "Please verify your email before logging in"))
+		return
+	}
+
+	accessToken, refreshToken, err := h.issueTokens(r.Context(), u.ID)
+	if err != nil {
+		log.Printf("Token issuance error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
+}
+
+// RefreshToken exchanges a valid, unrevoked refresh token for a new access
+// token without requiring the user to log in again.
+func (h *UserHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "Invalid JSON"))
+		return
+	}
+
+	userID, err := h.sessions.UserIDForToken(r.Context(), auth.HashRefreshToken(request.RefreshToken))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		apierr.WriteError(w, apierr.New(http.StatusUnauthorized, apierr.CodeUnauthorized, "Invalid or expired refresh token"))
 		return
 	}
 	if err != nil {
 		log.Printf("Database error: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
 		return
 	}
 
-	response := map[string]interface{}{
-		"id":         id,
-		"email":      email,
-		"name":       name,
-		"age":        age,
-		"created_at": createdAt.Format(time.RFC3339),
+	accessToken, err := auth.IssueAccessToken(userID)
+	if err != nil {
+		log.Printf("Token issuance error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(map[string]string{"access_token": accessToken})
+}
+
+// RevokeToken invalidates a refresh token so it can no longer be exchanged
+// for access tokens, e.g. on logout.
+func (h *UserHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "Invalid JSON"))
+		return
+	}
+
+	err := h.sessions.Revoke(r.Context(), auth.HashRefreshToken(request.RefreshToken))
+	if errors.Is(err, repository.ErrSessionNotFound) {
+		apierr.WriteError(w, apierr.New(http.StatusNotFound, apierr.CodeNotFound, "Token not found or already revoked"))
+		return
+	}
+	if err != nil {
+		log.Printf("Database error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// issueTokens signs a fresh access token and persists a new session row
+// backing a fresh refresh token for userID.
+func (h *UserHandler) issueTokens(ctx context.Context, userID int) (accessToken, refreshToken string, err error) {
+	accessToken, err = auth.IssueAccessToken(userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, digest, err := auth.NewRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	expiresAt := time.Now().Add(auth.RefreshTokenTTL * time.Second)
+	if err := h.sessions.Create(ctx, userID, digest, expiresAt); err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
 }
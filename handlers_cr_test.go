@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/mail"
+	"ksx2/trpo3_code_style/repository"
+	"ksx2/trpo3_code_style/service"
+)
+
+func newTestHandler() *UserHandler {
+	repo := repository.NewFakeUserRepo()
+	svc := service.NewUserService(repo, auth.NewHasher(4))
+	verification := service.NewVerificationService(repository.NewFakeVerificationTokenRepo(), repo, mail.LogMailer{}, "http://localhost")
+	return NewUserHandlerWithService(svc, verification, nil)
+}
+
+func TestCreateUser(t *testing.T) {
+	h := newTestHandler()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email":    "alice@example.com",
+		"password": "hunter22",
+		"name":     "Alice",
+		"age":      30,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.CreateUser(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateUserDuplicateEmail(t *testing.T) {
+	h := newTestHandler()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email":    "alice@example.com",
+		"password": "hunter22",
+		"name":     "Alice",
+		"age":      30,
+	})
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		h.CreateUser(w, req)
+		if i == 1 && w.Code != http.StatusConflict {
+			t.Fatalf("expected 409 on duplicate email, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	h := newTestHandler()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email":    "not-an-email",
+		"password": "short",
+		"name":     "",
+		"age":      5,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.CreateUser(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateUserRejectsOtherAccounts(t *testing.T) {
+	t.Setenv("JWT_SIGNING_SECRET", "test-signing-secret")
+	h := newTestHandler()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email": "alice@example.com", "password": "hunter22", "name": "Alice", "age": 30,
+	})
+	createReq := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	createW := httptest.NewRecorder()
+	h.CreateUser(createW, createReq)
+	if createW.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", createW.Code, createW.Body.String())
+	}
+
+	attackerToken, err := auth.IssueAccessToken(999)
+	if err != nil {
+		t.Fatalf("IssueAccessToken: %v", err)
+	}
+
+	updateBody, _ := json.Marshal(map[string]interface{}{"age": -999, "name": ""})
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewReader(updateBody))
+	req.Header.Set("Authorization", "Bearer "+attackerToken)
+	w := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 when updating another account, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateUserValidatesAge(t *testing.T) {
+	t.Setenv("JWT_SIGNING_SECRET", "test-signing-secret")
+	h := newTestHandler()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email": "alice@example.com", "password": "hunter22", "name": "Alice", "age": 30,
+	})
+	createReq := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	createW := httptest.NewRecorder()
+	h.CreateUser(createW, createReq)
+	if createW.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", createW.Code, createW.Body.String())
+	}
+
+	token, err := auth.IssueAccessToken(1)
+	if err != nil {
+		t.Fatalf("IssueAccessToken: %v", err)
+	}
+
+	updateBody, _ := json.Marshal(map[string]interface{}{"age": -999, "name": ""})
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", bytes.NewReader(updateBody))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetUserNotFound(t *testing.T) {
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/users/999", nil)
+	w := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestListUsersFiltersByEmail(t *testing.T) {
+	h := newTestHandler()
+
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		body, _ := json.Marshal(map[string]interface{}{
+			"email": email, "password": "hunter22", "name": "User", "age": 30,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		h.CreateUser(w, req)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users?email=alice@example.com", nil)
+	w := httptest.NewRecorder()
+	h.ListUsers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Users []map[string]interface{} `json:"users"`
+		Total int                      `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Users) != 1 {
+		t.Fatalf("expected exactly one matching user, got %+v", resp)
+	}
+}
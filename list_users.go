@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ksx2/trpo3_code_style/apierr"
+	"ksx2/trpo3_code_style/repository"
+)
+
+// ListUsers handles GET /users, supporting cursor-based pagination,
+// filtering by email/age/created_after and sorting via ?sort=created_at,-name.
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	params, err := parseListParams(r)
+	if err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, err.Error()))
+		return
+	}
+
+	result, err := h.service.ListUsers(r.Context(), params)
+	if err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	users := make([]map[string]interface{}, 0, len(result.Users))
+	for _, u := range result.Users {
+		users = append(users, map[string]interface{}{
+			"id":         u.ID,
+			"email":      u.Email,
+			"name":       u.Name,
+			"age":        u.Age,
+			"created_at": u.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"users":       users,
+		"next_cursor": result.NextCursor,
+		"total":       result.Total,
+	})
+}
+
+func parseListParams(r *http.Request) (repository.ListParams, error) {
+	q := r.URL.Query()
+	var params repository.ListParams
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return params, errInvalidListParam("limit")
+		}
+		params.Limit = n
+	}
+
+	if cursor := q.Get("cursor"); cursor != "" {
+		decoded, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			return params, errInvalidListParam("cursor")
+		}
+		params.Cursor = &decoded
+	}
+
+	params.Email = q.Get("email")
+
+	if minAge := q.Get("min_age"); minAge != "" {
+		n, err := strconv.Atoi(minAge)
+		if err != nil {
+			return params, errInvalidListParam("min_age")
+		}
+		params.MinAge = &n
+	}
+
+	if maxAge := q.Get("max_age"); maxAge != "" {
+		n, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return params, errInvalidListParam("max_age")
+		}
+		params.MaxAge = &n
+	}
+
+	if createdAfter := q.Get("created_after"); createdAfter != "" {
+		t, err := time.Parse(time.RFC3339, createdAfter)
+		if err != nil {
+			return params, errInvalidListParam("created_after")
+		}
+		params.CreatedAfter = &t
+	}
+
+	if sortParam := q.Get("sort"); sortParam != "" {
+		for _, field := range strings.Split(sortParam, ",") {
+			desc := strings.HasPrefix(field, "-")
+			params.Sort = append(params.Sort, repository.SortField{
+				Field: strings.TrimPrefix(field, "-"),
+				Desc:  desc,
+			})
+		}
+	}
+
+	return params, nil
+}
+
+func errInvalidListParam(name string) error {
+	return fmt.Errorf("invalid %s parameter", name)
+}
@@ -0,0 +1,13 @@
+package mail
+
+import "os"
+
+// NewFromEnv selects a Mailer based on MAIL_TRANSPORT: "smtp" uses
+// NewSMTPMailerFromEnv, anything else (including unset, for local dev)
+// falls back to LogMailer.
+func NewFromEnv() Mailer {
+	if os.Getenv("MAIL_TRANSPORT") == "smtp" {
+		return NewSMTPMailerFromEnv()
+	}
+	return LogMailer{}
+}
@@ -0,0 +1,15 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer writes emails to the log instead of sending them, for local
+// development where there's no SMTP relay configured.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("mail: to=%s subject=%q body=%q", to, subject, body)
+	return nil
+}
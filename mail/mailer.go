@@ -0,0 +1,11 @@
+// Package mail provides a pluggable transport for transactional emails
+// (currently just account verification) so local dev doesn't need a real
+// SMTP server.
+package mail
+
+import "context"
+
+// Mailer sends a single plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
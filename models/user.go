@@ -0,0 +1,22 @@
+// Package models holds the domain types shared by the repository, service
+// and handler layers.
+package models
+
+import "time"
+
+// User is a single row of the users table.
+type User struct {
+	ID           int
+	Email        string
+	PasswordHash string
+	Name         string
+	Age          int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	VerifiedAt   *time.Time
+}
+
+// IsVerified reports whether the user has completed email verification.
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt != nil
+}
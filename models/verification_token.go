@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// VerificationToken is a single row of the verification_tokens table. The
+// plaintext token is never stored, only its SHA-256 digest.
+type VerificationToken struct {
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+}
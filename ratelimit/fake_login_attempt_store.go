@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FakeLoginAttemptStore is an in-memory LoginAttemptStore for tests.
+type FakeLoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]*fakeLoginAttemptEntry
+}
+
+type fakeLoginAttemptEntry struct {
+	failureCount int
+	windowStart  time.Time
+	lockedUntil  time.Time
+}
+
+// NewFakeLoginAttemptStore builds an empty FakeLoginAttemptStore.
+func NewFakeLoginAttemptStore() *FakeLoginAttemptStore {
+	return &FakeLoginAttemptStore{entries: make(map[string]*fakeLoginAttemptEntry)}
+}
+
+func (s *FakeLoginAttemptStore) RecordFailure(ctx context.Context, emailHash string, maxAttempts int, window, cooldown time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	e, ok := s.entries[emailHash]
+	if !ok || now.Sub(e.windowStart) > window {
+		e = &fakeLoginAttemptEntry{windowStart: now}
+		s.entries[emailHash] = e
+	}
+
+	e.failureCount++
+	if e.failureCount >= maxAttempts {
+		e.lockedUntil = now.Add(cooldown)
+	}
+	return nil
+}
+
+func (s *FakeLoginAttemptStore) Reset(ctx context.Context, emailHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, emailHash)
+	return nil
+}
+
+func (s *FakeLoginAttemptStore) LockedUntil(ctx context.Context, emailHash string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[emailHash]
+	if !ok {
+		return time.Time{}, nil
+	}
+	return e.lockedUntil, nil
+}
@@ -0,0 +1,12 @@
+// Package ratelimit provides per-IP rate limiting and per-account
+// brute-force lockout middleware for the obvious abuse targets:
+// account creation and login.
+package ratelimit
+
+import "context"
+
+// Limiter decides whether a request identified by key (typically a
+// client IP) may proceed.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (bool, error)
+}
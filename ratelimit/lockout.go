@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LoginAttemptStore tracks failed login attempts per account so
+// AccountLockout can enforce a cooldown after too many in a row.
+type LoginAttemptStore interface {
+	// RecordFailure registers one more failed attempt for emailHash within
+	// window, locking the account out for cooldown once it reaches
+	// maxAttempts.
+	RecordFailure(ctx context.Context, emailHash string, maxAttempts int, window, cooldown time.Duration) error
+	// Reset clears the failure count after a successful login.
+	Reset(ctx context.Context, emailHash string) error
+	// LockedUntil reports the time an account's lockout expires, or the
+	// zero Time if it isn't currently locked.
+	LockedUntil(ctx context.Context, emailHash string) (time.Time, error)
+}
+
+// AccountLockout wraps a login handler so that repeated failed attempts
+// for the same email lock the account out for cooldown once maxAttempts
+// is reached within window. It inspects the response status the wrapped
+// handler writes: 401 counts as a failure, a status below 400 resets the
+// counter, and any other status leaves it unchanged.
+func AccountLockout(store LoginAttemptStore, maxAttempts int, window, cooldown time.Duration) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			emailHash, body, err := hashRequestEmail(r)
+			if err != nil {
+				next(w, r)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			lockedUntil, err := store.LockedUntil(r.Context(), emailHash)
+			if err == nil && !lockedUntil.IsZero() && time.Now().Before(lockedUntil) {
+				retryAfter := int(time.Until(lockedUntil).Seconds())
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{"code": "ACCOUNT_LOCKED", "message": "Too many failed attempts, try again later"})
+				return
+			}
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next(rec, r)
+
+			if rec.status == http.StatusUnauthorized {
+				_ = store.RecordFailure(r.Context(), emailHash, maxAttempts, window, cooldown)
+			} else if rec.status < 400 {
+				_ = store.Reset(r.Context(), emailHash)
+			}
+		}
+	}
+}
+
+func hashRequestEmail(r *http.Request) (emailHash string, body []byte, err error) {
+	body, err = io.ReadAll(r.Body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var payload struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", body, err
+	}
+
+	sum := sha256.Sum256([]byte(payload.Email))
+	return hex.EncodeToString(sum[:]), body, nil
+}
+
+// statusRecorder captures the status code a handler writes, so
+// AccountLockout can decide whether the attempt succeeded.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAccountLockoutLocksAfterMaxAttempts(t *testing.T) {
+	store := NewFakeLoginAttemptStore()
+	alwaysUnauthorized := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	handler := AccountLockout(store, 3, time.Minute, time.Minute)(alwaysUnauthorized)
+
+	body, _ := json.Marshal(map[string]string{"email": "alice@example.com", "password": "wrong"})
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected account to be locked out, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header on lockout response")
+	}
+}
+
+func TestAccountLockoutResetsOnSuccess(t *testing.T) {
+	store := NewFakeLoginAttemptStore()
+	outcomes := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusOK, http.StatusUnauthorized}
+	call := 0
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(outcomes[call])
+		call++
+	}
+	handler := AccountLockout(store, 3, time.Minute, time.Minute)(next)
+
+	body, _ := json.Marshal(map[string]string{"email": "alice@example.com", "password": "x"})
+
+	for range outcomes {
+		req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code == http.StatusTooManyRequests {
+			t.Fatal("account should not have locked out: success reset the failure count")
+		}
+	}
+}
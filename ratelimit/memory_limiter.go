@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// MemoryLimiter is a per-key token bucket limiter backed by
+// golang.org/x/time/rate. It's process-local: each instance in a
+// multi-instance deployment enforces its own independent bucket, which is
+// fine for a single instance but under-limits in aggregate once traffic
+// is spread across several. Use RedisLimiter there instead.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	perMinute int
+	burst     int
+}
+
+// NewMemoryLimiter builds a MemoryLimiter allowing perMinute requests per
+// key, with bursts up to perMinute.
+func NewMemoryLimiter(perMinute int) *MemoryLimiter {
+	return &MemoryLimiter{
+		limiters:  make(map[string]*rate.Limiter),
+		perMinute: perMinute,
+		burst:     perMinute,
+	}
+}
+
+func (m *MemoryLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	limiter, ok := m.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(m.perMinute)/60, m.burst)
+		m.limiters[key] = limiter
+	}
+	m.mu.Unlock()
+
+	return limiter.Allow(), nil
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryLimiterBlocksAfterBurst(t *testing.T) {
+	limiter := NewMemoryLimiter(2)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, err := limiter.Allow(ctx, "1.2.3.4")
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !allowed {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+
+	allowed, err := limiter.Allow(ctx, "1.2.3.4")
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if allowed {
+		t.Fatal("expected burst to be exhausted")
+	}
+}
+
+func TestMemoryLimiterTracksKeysIndependently(t *testing.T) {
+	limiter := NewMemoryLimiter(1)
+	ctx := context.Background()
+
+	if allowed, _ := limiter.Allow(ctx, "a"); !allowed {
+		t.Fatal("expected first key's first request to be allowed")
+	}
+	if allowed, _ := limiter.Allow(ctx, "b"); !allowed {
+		t.Fatal("expected second key's first request to be allowed regardless of the first key's state")
+	}
+}
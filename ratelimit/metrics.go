@@ -0,0 +1,17 @@
+package ratelimit
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// requestsTotal lets operators watch acceptance/rejection rates per route
+// and tune perMinute thresholds without guessing from error logs.
+var requestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ratelimit_requests_total",
+		Help: "Requests seen by the rate limiter, labeled by route and outcome.",
+	},
+	[]string{"route", "outcome"},
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal)
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// PostgresLoginAttemptStore persists failed login attempts to the
+// login_attempts table.
+type PostgresLoginAttemptStore struct {
+	db *sql.DB
+}
+
+// NewPostgresLoginAttemptStore builds a PostgresLoginAttemptStore backed
+// by db.
+func NewPostgresLoginAttemptStore(db *sql.DB) *PostgresLoginAttemptStore {
+	return &PostgresLoginAttemptStore{db: db}
+}
+
+// RecordFailure increments emailHash's failure count and, once it reaches
+// maxAttempts, locks it out until cooldown elapses. The read-modify-write
+// runs inside a transaction that locks the row with SELECT ... FOR UPDATE,
+// so concurrent failed logins for the same email are serialized instead of
+// racing and undercounting.
+func (s *PostgresLoginAttemptStore) RecordFailure(ctx context.Context, emailHash string, maxAttempts int, window, cooldown time.Duration) error {
+	now := time.Now()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO login_attempts (email_hash, failure_count, window_start, locked_until)
+		VALUES ($1, 0, $2, NULL)
+		ON CONFLICT (email_hash) DO NOTHING`,
+		emailHash, now,
+	); err != nil {
+		return err
+	}
+
+	var failureCount int
+	var windowStart time.Time
+	if err := tx.QueryRowContext(ctx,
+		"SELECT failure_count, window_start FROM login_attempts WHERE email_hash = $1 FOR UPDATE", emailHash,
+	).Scan(&failureCount, &windowStart); err != nil {
+		return err
+	}
+
+	if now.Sub(windowStart) > window {
+		failureCount, windowStart = 0, now
+	}
+	failureCount++
+
+	var lockedUntil *time.Time
+	if failureCount >= maxAttempts {
+		until := now.Add(cooldown)
+		lockedUntil = &until
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE login_attempts SET failure_count = $2, window_start = $3, locked_until = $4 WHERE email_hash = $1",
+		emailHash, failureCount, windowStart, lockedUntil,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *PostgresLoginAttemptStore) Reset(ctx context.Context, emailHash string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM login_attempts WHERE email_hash = $1", emailHash)
+	return err
+}
+
+func (s *PostgresLoginAttemptStore) LockedUntil(ctx context.Context, emailHash string) (time.Time, error) {
+	var lockedUntil sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		"SELECT locked_until FROM login_attempts WHERE email_hash = $1", emailHash,
+	).Scan(&lockedUntil)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !lockedUntil.Valid {
+		return time.Time{}, nil
+	}
+	return lockedUntil.Time, nil
+}
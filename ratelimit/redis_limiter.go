@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLimiter implements a fixed-window rate limit shared across every
+// instance, via an INCR per request plus an EXPIRE on the first request of
+// each window. It is not a sliding window: a client can spend up to twice
+// the limit across the boundary between two adjacent windows.
+type RedisLimiter struct {
+	client    *redis.Client
+	perMinute int
+	route     string
+}
+
+// NewRedisLimiter builds a RedisLimiter allowing perMinute requests per
+// key per fixed one-minute window, shared across every process pointed at
+// client.
+// route scopes the Redis keyspace so distinct endpoints sharing the same
+// RateLimit call pattern (and thus the same client IP) don't drain each
+// other's budget.
+func NewRedisLimiter(client *redis.Client, perMinute int, route string) *RedisLimiter {
+	return &RedisLimiter{client: client, perMinute: perMinute, route: route}
+}
+
+// newRedisClientFromEnv builds a client for addr, using REDIS_DB (default
+// 0) to pick the logical database.
+func newRedisClientFromEnv(addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: addr,
+		DB:   envInt("REDIS_DB", 0),
+	})
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (bool, error) {
+	redisKey := fmt.Sprintf("ratelimit:%s:%s", l.route, key)
+
+	count, err := l.client.Incr(ctx, redisKey).Result()
+	if err != nil {
+		return false, err
+	}
+	if count == 1 {
+		if err := l.client.Expire(ctx, redisKey, time.Minute).Err(); err != nil {
+			return false, err
+		}
+	}
+
+	return count <= int64(l.perMinute), nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for a malformed cursor
+// string.
+var ErrInvalidCursor = errors.New("repository: invalid cursor")
+
+// Cursor identifies a position in the ordering used for ListUsers
+// pagination: the value of every field the page was sorted by, plus id,
+// which is always the final tiebreaker. Only the fields that were actually
+// part of the requested sort are populated; the rest are left zero and
+// ignored by effectiveSortKeys.
+type Cursor struct {
+	LastID        int       `json:"last_id"`
+	LastCreatedAt time.Time `json:"last_created_at,omitempty"`
+	LastName      string    `json:"last_name,omitempty"`
+	LastAge       int       `json:"last_age,omitempty"`
+}
+
+// cursorFromUser builds the Cursor for the last row of a page, so the next
+// page's WHERE clause can resume the exact same (sort, id) ordering.
+func cursorFromUser(u *models.User, sort []SortField) Cursor {
+	c := Cursor{LastID: u.ID}
+	for _, f := range effectiveSortKeys(sort) {
+		switch f.Field {
+		case "name":
+			c.LastName = u.Name
+		case "age":
+			c.LastAge = u.Age
+		case "created_at":
+			c.LastCreatedAt = u.CreatedAt
+		}
+	}
+	return c
+}
+
+// cursorFieldValue returns c's value for field, one of the columns
+// effectiveSortKeys can produce.
+func cursorFieldValue(c Cursor, field string) interface{} {
+	switch field {
+	case "name":
+		return c.LastName
+	case "age":
+		return c.LastAge
+	case "id":
+		return c.LastID
+	default: // created_at
+		return c.LastCreatedAt
+	}
+}
+
+// EncodeCursor base64-encodes c as an opaque pagination token.
+func EncodeCursor(c Cursor) (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(raw), nil
+}
+
+// DecodeCursor parses an opaque pagination token produced by EncodeCursor.
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type fakeSession struct {
+	userID    int
+	expiresAt time.Time
+	revoked   bool
+}
+
+// FakeSessionRepo is an in-memory SessionRepo for unit tests that don't
+// need a real Postgres instance.
+type FakeSessionRepo struct {
+	mu       sync.Mutex
+	sessions map[string]*fakeSession
+}
+
+// NewFakeSessionRepo builds an empty FakeSessionRepo.
+func NewFakeSessionRepo() *FakeSessionRepo {
+	return &FakeSessionRepo{sessions: make(map[string]*fakeSession)}
+}
+
+func (r *FakeSessionRepo) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[tokenHash] = &fakeSession{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *FakeSessionRepo) UserIDForToken(ctx context.Context, tokenHash string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[tokenHash]
+	if !ok || s.revoked || time.Now().After(s.expiresAt) {
+		return 0, ErrSessionNotFound
+	}
+	return s.userID, nil
+}
+
+func (r *FakeSessionRepo) Revoke(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[tokenHash]
+	if !ok || s.revoked {
+		return ErrSessionNotFound
+	}
+	s.revoked = true
+	return nil
+}
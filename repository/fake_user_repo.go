@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// FakeUserRepo is an in-memory UserRepo for unit tests that don't need a
+// real Postgres instance.
+type FakeUserRepo struct {
+	mu     sync.Mutex
+	nextID int
+	users  map[int]*models.User
+}
+
+// NewFakeUserRepo builds an empty FakeUserRepo.
+func NewFakeUserRepo() *FakeUserRepo {
+	return &FakeUserRepo{users: make(map[int]*models.User)}
+}
+
+func (r *FakeUserRepo) Create(ctx context.Context, u *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	u.ID = r.nextID
+	clone := *u
+	r.users[u.ID] = &clone
+	return nil
+}
+
+func (r *FakeUserRepo) FindByID(ctx context.Context, id int) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *u
+	return &clone, nil
+}
+
+func (r *FakeUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Email == email {
+			clone := *u
+			return &clone, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *FakeUserRepo) List(ctx context.Context, params ListParams) (*ListResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matched []*models.User
+	for _, u := range r.users {
+		if params.Email != "" && u.Email != params.Email {
+			continue
+		}
+		if params.MinAge != nil && u.Age < *params.MinAge {
+			continue
+		}
+		if params.MaxAge != nil && u.Age > *params.MaxAge {
+			continue
+		}
+		if params.CreatedAfter != nil && !u.CreatedAt.After(*params.CreatedAfter) {
+			continue
+		}
+		clone := *u
+		matched = append(matched, &clone)
+	}
+	total := len(matched)
+
+	sort.Slice(matched, func(i, j int) bool {
+		for _, f := range params.Sort {
+			if !SortableFields[f.Field] {
+				continue
+			}
+			less, equal := compareField(matched[i], matched[j], f.Field)
+			if equal {
+				continue
+			}
+			if f.Desc {
+				return !less
+			}
+			return less
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	if params.Cursor != nil {
+		matched = afterCursor(matched, params.Sort, *params.Cursor)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var nextCursor string
+	if len(matched) > limit {
+		last := matched[limit-1]
+		token, err := EncodeCursor(cursorFromUser(last, params.Sort))
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = token
+		matched = matched[:limit]
+	}
+
+	return &ListResult{Users: matched, NextCursor: nextCursor, Total: total}, nil
+}
+
+func compareField(a, b *models.User, field string) (less, equal bool) {
+	switch field {
+	case "name":
+		return a.Name < b.Name, a.Name == b.Name
+	case "age":
+		return a.Age < b.Age, a.Age == b.Age
+	default: // created_at
+		return a.CreatedAt.Before(b.CreatedAt), a.CreatedAt.Equal(b.CreatedAt)
+	}
+}
+
+// afterCursor filters users down to the ones that sort after cursor under
+// the same (sort, id) ordering used to build it, mirroring the Postgres
+// keyset WHERE clause built by buildCursorCondition.
+func afterCursor(users []*models.User, sort []SortField, cursor Cursor) []*models.User {
+	keys := effectiveSortKeys(sort)
+
+	var out []*models.User
+	for _, u := range users {
+		if isAfterCursor(u, keys, cursor) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+func isAfterCursor(u *models.User, keys []SortField, cursor Cursor) bool {
+	for _, k := range keys {
+		less, equal := compareCursorField(u, k.Field, cursor)
+		if equal {
+			continue
+		}
+		if k.Desc {
+			return less
+		}
+		return !less
+	}
+	return false
+}
+
+func compareCursorField(a *models.User, field string, cursor Cursor) (less, equal bool) {
+	switch field {
+	case "name":
+		return a.Name < cursor.LastName, a.Name == cursor.LastName
+	case "age":
+		return a.Age < cursor.LastAge, a.Age == cursor.LastAge
+	case "id":
+		return a.ID < cursor.LastID, a.ID == cursor.LastID
+	default: // created_at
+		return a.CreatedAt.Before(cursor.LastCreatedAt), a.CreatedAt.Equal(cursor.LastCreatedAt)
+	}
+}
+
+func (r *FakeUserRepo) Update(ctx context.Context, u *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[u.ID]; !ok {
+		return ErrNotFound
+	}
+	clone := *u
+	r.users[u.ID] = &clone
+	return nil
+}
+
+func (r *FakeUserRepo) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *FakeUserRepo) MarkVerified(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	now := time.Now()
+	u.VerifiedAt = &now
+	return nil
+}
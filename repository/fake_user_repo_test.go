@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+func TestFakeUserRepoListCursorRespectsNonDefaultSort(t *testing.T) {
+	repo := NewFakeUserRepo()
+	ctx := context.Background()
+
+	names := []string{"carol", "alice", "bob"}
+	for i, name := range names {
+		u := &models.User{
+			Email:     name + "@example.com",
+			Name:      name,
+			Age:       20,
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Second),
+		}
+		if err := repo.Create(ctx, u); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	params := ListParams{Limit: 1, Sort: []SortField{{Field: "name"}}}
+
+	var seen []string
+	for {
+		result, err := repo.List(ctx, params)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, u := range result.Users {
+			seen = append(seen, u.Name)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor, err := DecodeCursor(result.NextCursor)
+		if err != nil {
+			t.Fatalf("DecodeCursor: %v", err)
+		}
+		params.Cursor = &cursor
+	}
+
+	want := []string{"alice", "bob", "carol"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected %v, got %v", want, seen)
+	}
+	for i, name := range want {
+		if seen[i] != name {
+			t.Fatalf("expected %v, got %v", want, seen)
+		}
+	}
+}
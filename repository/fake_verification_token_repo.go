@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// FakeVerificationTokenRepo is an in-memory VerificationTokenRepo for tests.
+type FakeVerificationTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*models.VerificationToken
+}
+
+// NewFakeVerificationTokenRepo builds an empty FakeVerificationTokenRepo.
+func NewFakeVerificationTokenRepo() *FakeVerificationTokenRepo {
+	return &FakeVerificationTokenRepo{tokens: make(map[string]*models.VerificationToken)}
+}
+
+func (r *FakeVerificationTokenRepo) Create(ctx context.Context, t *models.VerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *t
+	r.tokens[t.TokenHash] = &clone
+	return nil
+}
+
+func (r *FakeVerificationTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*models.VerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	clone := *t
+	return &clone, nil
+}
+
+func (r *FakeVerificationTokenRepo) DeleteByHash(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.tokens, tokenHash)
+	return nil
+}
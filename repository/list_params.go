@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"time"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// SortableFields whitelists the columns ListParams.Sort may reference, so
+// untrusted sort input can never be interpolated into SQL beyond this set.
+var SortableFields = map[string]bool{
+	"created_at": true,
+	"name":       true,
+	"age":        true,
+}
+
+// SortField is a single "field" or "-field" component of a ?sort= query
+// parameter, e.g. "-name" sorts descending by name.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListParams filters, sorts and paginates a call to UserRepo.List.
+type ListParams struct {
+	Limit        int
+	Cursor       *Cursor
+	Email        string
+	MinAge       *int
+	MaxAge       *int
+	CreatedAfter *time.Time
+	Sort         []SortField
+}
+
+// ListResult is the page of users returned by UserRepo.List, along with
+// enough information for the caller to fetch the next page.
+type ListResult struct {
+	Users      []*models.User
+	NextCursor string
+	Total      int
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// postgresSessionRepo is the Postgres-backed SessionRepo implementation
+// used in production.
+type postgresSessionRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresSessionRepo builds a SessionRepo backed by db.
+func NewPostgresSessionRepo(db *sql.DB) SessionRepo {
+	return &postgresSessionRepo{db: db}
+}
+
+func (r *postgresSessionRepo) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO sessions (user_id, token_hash, expires_at) VALUES ($1, $2, $3)",
+		userID, tokenHash, expiresAt,
+	)
+	return err
+}
+
+func (r *postgresSessionRepo) UserIDForToken(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id FROM sessions
+		 WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > now()`,
+		tokenHash,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrSessionNotFound
+	}
+	return userID, err
+}
+
+func (r *postgresSessionRepo) Revoke(ctx context.Context, tokenHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET revoked_at = now() WHERE token_hash = $1 AND revoked_at IS NULL",
+		tokenHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// countQueryTimeout bounds the auxiliary COUNT(*) query issued alongside a
+// ListUsers page, so a slow count on a large table can't stall the request.
+const countQueryTimeout = 2 * time.Second
+
+// postgresUserRepo is the Postgres-backed UserRepo implementation used in
+// production.
+type postgresUserRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresUserRepo builds a UserRepo backed by db.
+func NewPostgresUserRepo(db *sql.DB) UserRepo {
+	return &postgresUserRepo{db: db}
+}
+
+func (r *postgresUserRepo) Create(ctx context.Context, u *models.User) error {
+	query := `INSERT INTO users (email, password_hash, name, age, created_at)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	return r.db.QueryRowContext(ctx, query, u.Email, u.PasswordHash, u.Name, u.Age, u.CreatedAt).Scan(&u.ID)
+}
+
+const userColumns = "id, email, password_hash, name, age, created_at, verified_at"
+
+func (r *postgresUserRepo) FindByID(ctx context.Context, id int) (*models.User, error) {
+	return r.scanOne(r.db.QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE id = $1", id))
+}
+
+func (r *postgresUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.scanOne(r.db.QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE email = $1", email))
+}
+
+func (r *postgresUserRepo) List(ctx context.Context, params ListParams) (*ListResult, error) {
+	q := buildListConditions(params)
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM users %s %s LIMIT %d",
+		userColumns, q.where(), orderByClause(params.Sort), limit+1,
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		u, verifiedAt := &models.User{}, sql.NullTime{}
+		if err := rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.Age, &u.CreatedAt, &verifiedAt); err != nil {
+			return nil, err
+		}
+		if verifiedAt.Valid {
+			u.VerifiedAt = &verifiedAt.Time
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var nextCursor string
+	if len(users) > limit {
+		last := users[limit-1]
+		nextCursor, err = EncodeCursor(cursorFromUser(last, params.Sort))
+		if err != nil {
+			return nil, err
+		}
+		users = users[:limit]
+	}
+
+	total, err := r.countUsers(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListResult{Users: users, NextCursor: nextCursor, Total: total}, nil
+}
+
+// countUsers runs the filter-only (no cursor, no limit) COUNT(*) query used
+// for ListResult.Total, bounded by countQueryTimeout.
+func (r *postgresUserRepo) countUsers(ctx context.Context, params ListParams) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, countQueryTimeout)
+	defer cancel()
+
+	countParams := params
+	countParams.Cursor = nil
+	q := buildListConditions(countParams)
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM users %s", q.where())
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, q.args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *postgresUserRepo) Update(ctx context.Context, u *models.User) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE users SET email = $1, name = $2, age = $3 WHERE id = $4",
+		u.Email, u.Name, u.Age, u.ID)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+func (r *postgresUserRepo) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+func (r *postgresUserRepo) scanOne(row *sql.Row) (*models.User, error) {
+	u, verifiedAt := &models.User{}, sql.NullTime{}
+	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Name, &u.Age, &u.CreatedAt, &verifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if verifiedAt.Valid {
+		u.VerifiedAt = &verifiedAt.Time
+	}
+	return u, nil
+}
+
+func (r *postgresUserRepo) MarkVerified(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE users SET verified_at = now() WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireRowsAffected(result)
+}
+
+func requireRowsAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSessionNotFound is returned by UserIDForToken when no matching,
+// unrevoked, unexpired session row exists, and by Revoke when no matching,
+// unrevoked row exists (expiry is not checked there).
+var ErrSessionNotFound = errors.New("repository: session not found")
+
+// SessionRepo persists refresh token sessions. Implementations must
+// translate "no rows" conditions into ErrSessionNotFound rather than
+// leaking a driver-specific sentinel error.
+type SessionRepo interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) error
+	UserIDForToken(ctx context.Context, tokenHash string) (int, error)
+	Revoke(ctx context.Context, tokenHash string) error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// userListQuery incrementally builds the WHERE clause shared by the
+// ListUsers data query and its paired COUNT(*) query, using numbered
+// placeholders so values are always passed as parameters, never
+// interpolated into the SQL string.
+type userListQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *userListQuery) add(condition string, args ...interface{}) {
+	for _, arg := range args {
+		q.args = append(q.args, arg)
+		condition = strings.Replace(condition, "?", fmt.Sprintf("$%d", len(q.args)), 1)
+	}
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *userListQuery) where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+func buildListConditions(params ListParams) *userListQuery {
+	q := &userListQuery{}
+
+	if params.Email != "" {
+		q.add("email = ?", params.Email)
+	}
+	if params.MinAge != nil {
+		q.add("age >= ?", *params.MinAge)
+	}
+	if params.MaxAge != nil {
+		q.add("age <= ?", *params.MaxAge)
+	}
+	if params.CreatedAfter != nil {
+		q.add("created_at > ?", *params.CreatedAfter)
+	}
+	if params.Cursor != nil {
+		condition, args := buildCursorCondition(effectiveSortKeys(params.Sort), *params.Cursor)
+		q.add(condition, args...)
+	}
+
+	return q
+}
+
+// effectiveSortKeys filters sort down to the whitelisted fields and appends
+// the implicit "id ASC" tiebreaker. orderByClause and buildCursorCondition
+// are both built from this same list, so the ORDER BY and the keyset WHERE
+// clause can never drift out of sync with each other.
+func effectiveSortKeys(sort []SortField) []SortField {
+	keys := make([]SortField, 0, len(sort)+1)
+	for _, f := range sort {
+		if !SortableFields[f.Field] {
+			continue
+		}
+		keys = append(keys, f)
+	}
+	keys = append(keys, SortField{Field: "id"})
+	return keys
+}
+
+// orderByClause renders keys (see effectiveSortKeys) into an ORDER BY
+// clause.
+func orderByClause(sort []SortField) string {
+	keys := effectiveSortKeys(sort)
+	parts := make([]string, 0, len(keys))
+	for _, f := range keys {
+		direction := "ASC"
+		if f.Desc {
+			direction = "DESC"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", f.Field, direction))
+	}
+	return "ORDER BY " + strings.Join(parts, ", ")
+}
+
+// buildCursorCondition renders the standard keyset-pagination predicate for
+// keys: a row sorts after cursor iff, at the first key where they differ,
+// it's on the forward side of that key's direction. Each disjunct pins the
+// preceding keys equal and applies a strict comparison to its own key, so
+// the whole OR-of-ANDs is equivalent to a tuple comparison that also
+// supports per-column sort directions.
+func buildCursorCondition(keys []SortField, cursor Cursor) (string, []interface{}) {
+	var orParts []string
+	var args []interface{}
+	for i, k := range keys {
+		var eqParts []string
+		for _, prior := range keys[:i] {
+			eqParts = append(eqParts, fmt.Sprintf("%s = ?", prior.Field))
+			args = append(args, cursorFieldValue(cursor, prior.Field))
+		}
+		op := ">"
+		if k.Desc {
+			op = "<"
+		}
+		eqParts = append(eqParts, fmt.Sprintf("%s %s ?", k.Field, op))
+		args = append(args, cursorFieldValue(cursor, k.Field))
+		orParts = append(orParts, "("+strings.Join(eqParts, " AND ")+")")
+	}
+	return "(" + strings.Join(orParts, " OR ") + ")", args
+}
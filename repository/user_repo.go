@@ -0,0 +1,26 @@
+// Package repository isolates user persistence behind an interface so the
+// service layer can be tested without a real Postgres instance.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// ErrNotFound is returned by UserRepo methods when no matching row exists.
+var ErrNotFound = errors.New("repository: user not found")
+
+// UserRepo persists and retrieves users. Implementations must translate
+// "no rows" conditions into ErrNotFound rather than leaking a driver-
+// specific sentinel error.
+type UserRepo interface {
+	Create(ctx context.Context, u *models.User) error
+	FindByID(ctx context.Context, id int) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	List(ctx context.Context, params ListParams) (*ListResult, error)
+	Update(ctx context.Context, u *models.User) error
+	Delete(ctx context.Context, id int) error
+	MarkVerified(ctx context.Context, id int) error
+}
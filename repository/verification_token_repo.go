@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"ksx2/trpo3_code_style/models"
+)
+
+// VerificationTokenRepo persists email verification tokens.
+type VerificationTokenRepo interface {
+	Create(ctx context.Context, t *models.VerificationToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.VerificationToken, error)
+	DeleteByHash(ctx context.Context, tokenHash string) error
+}
+
+type postgresVerificationTokenRepo struct {
+	db *sql.DB
+}
+
+// NewPostgresVerificationTokenRepo builds a VerificationTokenRepo backed by db.
+func NewPostgresVerificationTokenRepo(db *sql.DB) VerificationTokenRepo {
+	return &postgresVerificationTokenRepo{db: db}
+}
+
+func (r *postgresVerificationTokenRepo) Create(ctx context.Context, t *models.VerificationToken) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO verification_tokens (token_hash, user_id, expires_at) VALUES ($1, $2, $3)",
+		t.TokenHash, t.UserID, t.ExpiresAt)
+	return err
+}
+
+func (r *postgresVerificationTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*models.VerificationToken, error) {
+	t := &models.VerificationToken{TokenHash: tokenHash}
+	err := r.db.QueryRowContext(ctx,
+		"SELECT user_id, expires_at FROM verification_tokens WHERE token_hash = $1", tokenHash,
+	).Scan(&t.UserID, &t.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *postgresVerificationTokenRepo) DeleteByHash(ctx context.Context, tokenHash string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM verification_tokens WHERE token_hash = $1", tokenHash)
+	return err
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/ratelimit"
+)
+
+// loginLockoutThreshold/Window/Cooldown bound how many failed logins an
+// email may accrue before it's locked out, and for how long.
+const (
+	loginLockoutThreshold = 5
+	loginLockoutWindow    = 15 * time.Minute
+	loginLockoutCooldown  = 15 * time.Minute
+)
+
+// requestTimeout bounds how long any single request may run: once it
+// elapses the request context is cancelled and the client gets a 504.
+const requestTimeout = 10 * time.Second
+
+// Router builds the full set of user routes with logging, panic recovery,
+// CORS and a request timeout already wired in, so main.go only needs to
+// mount it.
+func (h *UserHandler) Router() chi.Router {
+	r := chi.NewRouter()
+
+	r.Use(chimiddleware.RequestID)
+	r.Use(chimiddleware.Logger)
+	r.Use(chimiddleware.Recoverer)
+	r.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: false,
+		MaxAge:           300,
+	}))
+	r.Use(chimiddleware.Timeout(requestTimeout))
+
+	loginLockout := ratelimit.AccountLockout(h.loginAttempts, loginLockoutThreshold, loginLockoutWindow, loginLockoutCooldown)
+
+	r.With(ratelimit.RateLimit(20, "login")).Post("/login", loginLockout(h.LoginUser))
+	r.Post("/token/refresh", h.RefreshToken)
+	r.Post("/token/revoke", h.RevokeToken)
+	r.Get("/verify", h.VerifyEmail)
+	r.With(auth.Middleware).Get("/me", h.GetUser)
+
+	r.Route("/users", func(r chi.Router) {
+		r.With(ratelimit.RateLimit(10, "users:create")).Post("/", h.CreateUser)
+		r.Get("/", h.ListUsers)
+		r.Get("/{id}", h.GetUser)
+		r.With(auth.Middleware).Patch("/{id}", h.UpdateUser)
+		r.With(auth.Middleware).Delete("/{id}", h.DeleteUser)
+		r.With(auth.Middleware, ratelimit.RateLimit(10, "verify:resend")).Post("/{id}/verify/resend", h.ResendVerification)
+	})
+
+	return r
+}
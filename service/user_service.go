@@ -0,0 +1,122 @@
+// Package service holds the business logic that sits between the HTTP
+// handlers and the repository layer: uniqueness checks, password hashing,
+// timestamps and credential verification.
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/models"
+	"ksx2/trpo3_code_style/repository"
+)
+
+// ErrEmailTaken is returned by CreateUser when the email is already
+// registered.
+var ErrEmailTaken = errors.New("service: email already registered")
+
+// ErrNotFound is returned when the requested user does not exist. It is
+// the same value as repository.ErrNotFound, so callers can check either.
+var ErrNotFound = repository.ErrNotFound
+
+// ErrInvalidCredentials is returned by Authenticate on a bad email/password
+// combination. It deliberately doesn't distinguish "no such user" from
+// "wrong password" so callers can't use it to enumerate accounts.
+var ErrInvalidCredentials = errors.New("service: invalid credentials")
+
+// UserService implements the CreateUser/GetUser/Authenticate business
+// logic on top of a UserRepo.
+type UserService struct {
+	repo   repository.UserRepo
+	hasher *auth.Hasher
+}
+
+// NewUserService builds a UserService backed by repo, hashing passwords
+// with hasher.
+func NewUserService(repo repository.UserRepo, hasher *auth.Hasher) *UserService {
+	return &UserService{repo: repo, hasher: hasher}
+}
+
+// CreateUser validates email uniqueness, hashes the password and persists
+// a new user.
+func (s *UserService) CreateUser(ctx context.Context, email, password, name string, age int) (*models.User, error) {
+	_, err := s.repo.FindByEmail(ctx, email)
+	if err == nil {
+		return nil, ErrEmailTaken
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	hash, err := s.hasher.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &models.User{
+		Email:        email,
+		PasswordHash: hash,
+		Name:         name,
+		Age:          age,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// GetUser fetches a user by id.
+func (s *UserService) GetUser(ctx context.Context, id int) (*models.User, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListUsers delegates to the repository's filtered, sorted, paginated
+// listing; there's no business logic to apply here beyond what the
+// repository already enforces (e.g. the sortable field whitelist).
+func (s *UserService) ListUsers(ctx context.Context, params repository.ListParams) (*repository.ListResult, error) {
+	return s.repo.List(ctx, params)
+}
+
+// UpdateUser applies a partial update: nil fields are left unchanged.
+func (s *UserService) UpdateUser(ctx context.Context, id int, name *string, age *int) (*models.User, error) {
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != nil {
+		u.Name = *name
+	}
+	if age != nil {
+		u.Age = *age
+	}
+
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// DeleteUser removes a user by id.
+func (s *UserService) DeleteUser(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Authenticate verifies email/password and returns the matching user.
+func (s *UserService) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	u, err := s.repo.FindByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.hasher.VerifyPassword(u.PasswordHash, password); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return u, nil
+}
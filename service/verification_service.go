@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/mail"
+	"ksx2/trpo3_code_style/models"
+	"ksx2/trpo3_code_style/repository"
+)
+
+// ErrTokenExpired is returned by VerifyEmail when the token was valid but
+// has passed its TTL.
+var ErrTokenExpired = errors.New("service: verification token expired")
+
+// VerificationService drives the email-verification flow: issuing
+// one-time tokens, emailing them, and flipping users.verified_at once a
+// token is redeemed.
+type VerificationService struct {
+	tokens  repository.VerificationTokenRepo
+	users   repository.UserRepo
+	mailer  mail.Mailer
+	baseURL string
+}
+
+// NewVerificationService builds a VerificationService. baseURL is prefixed
+// to the verification link embedded in the email, e.g.
+// "https://api.example.com".
+func NewVerificationService(tokens repository.VerificationTokenRepo, users repository.UserRepo, mailer mail.Mailer, baseURL string) *VerificationService {
+	return &VerificationService{tokens: tokens, users: users, mailer: mailer, baseURL: baseURL}
+}
+
+// SendVerificationEmail issues a fresh token for userID and emails a
+// verification link to their address on file.
+func (s *VerificationService) SendVerificationEmail(ctx context.Context, userID int) error {
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	token, digest, err := auth.NewVerificationToken()
+	if err != nil {
+		return err
+	}
+
+	err = s.tokens.Create(ctx, &models.VerificationToken{
+		UserID:    userID,
+		TokenHash: digest,
+		ExpiresAt: time.Now().Add(auth.VerificationTokenTTL * time.Second),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify?token=%s", s.baseURL, token)
+	body := fmt.Sprintf("Hi %s,\n\nVerify your email by visiting: %s\n\nThis link expires in 24 hours.", u.Name, link)
+	return s.mailer.Send(ctx, u.Email, "Verify your email address", body)
+}
+
+// VerifyEmail redeems token, marking the owning user as verified. The
+// token is deleted whether or not it had already expired, so a single
+// link can't be retried indefinitely.
+func (s *VerificationService) VerifyEmail(ctx context.Context, token string) error {
+	digest := auth.HashVerificationToken(token)
+
+	t, err := s.tokens.FindByHash(ctx, digest)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	defer s.tokens.DeleteByHash(ctx, digest)
+
+	if time.Now().After(t.ExpiresAt) {
+		return ErrTokenExpired
+	}
+
+	return s.users.MarkVerified(ctx, t.UserID)
+}
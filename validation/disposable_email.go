@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// disposableEmailDomains is a minimal blocklist of well-known throwaway
+// email providers. It's intentionally small; extend it as abuse patterns
+// are observed rather than trying to be exhaustive up front.
+var disposableEmailDomains = map[string]bool{
+	"mailinator.com":    true,
+	"10minutemail.com":  true,
+	"guerrillamail.com": true,
+	"tempmail.com":      true,
+	"yopmail.com":       true,
+}
+
+func notDisposableEmail(fl validator.FieldLevel) bool {
+	email := fl.Field().String()
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return true
+	}
+	domain := strings.ToLower(email[at+1:])
+	return !disposableEmailDomains[domain]
+}
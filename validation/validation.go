@@ -0,0 +1,96 @@
+// Package validation provides struct-tag based request validation for
+// handler DTOs, collecting every violation instead of stopping at the
+// first one.
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a single validation violation on one field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is a collection of FieldError that implements error. A zero-value
+// or empty Errors is never returned by Validate; callers should treat a nil
+// error as "no violations".
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validatable is implemented by request DTOs that can validate themselves.
+type Validatable interface {
+	Validate() error
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	_ = v.RegisterValidation("not_disposable_email", notDisposableEmail)
+	_ = v.RegisterValidation("unicode_name", unicodeName)
+	return v
+}
+
+// Struct validates s against its `validate` struct tags and returns an
+// Errors value collecting every violation, or nil if s is valid.
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return Errors{{Field: "_", Message: err.Error()}}
+	}
+
+	out := make(Errors, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		out = append(out, FieldError{
+			Field:   fe.Field(),
+			Message: messageFor(fe),
+		})
+	}
+	return out
+}
+
+func messageFor(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "not_disposable_email":
+		return "disposable email addresses are not allowed"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "unicode_name":
+		return "must be between 1 and 100 characters"
+	default:
+		return fmt.Sprintf("failed %q validation", fe.Tag())
+	}
+}
+
+// unicodeName bounds name length by rune count rather than byte count, so
+// multi-byte names aren't penalized relative to ASCII ones.
+func unicodeName(fl validator.FieldLevel) bool {
+	n := utf8.RuneCountInString(strings.TrimSpace(fl.Field().String()))
+	return n > 0 && n <= 100
+}
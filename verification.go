@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ksx2/trpo3_code_style/apierr"
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/service"
+)
+
+// ResendVerification re-issues a verification email for the given user,
+// e.g. because the original expired or was lost.
+func (h *UserHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "User ID must be numeric"))
+		return
+	}
+
+	if authedID, ok := auth.UserIDFromContext(r.Context()); !ok || authedID != id {
+		apierr.WriteError(w, apierr.New(http.StatusForbidden, apierr.CodeUnauthorized, "You may only request verification for your own account"))
+		return
+	}
+
+	if err := h.verification.SendVerificationEmail(r.Context(), id); errors.Is(err, service.ErrNotFound) {
+		apierr.WriteError(w, apierr.New(http.StatusNotFound, apierr.CodeNotFound, "User not found"))
+		return
+	} else if err != nil {
+		log.Printf("SendVerificationEmail error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail handles GET /verify?token=..., redeeming the one-time
+// verification token and marking the owning user as verified.
+func (h *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		apierr.WriteError(w, apierr.New(http.StatusBadRequest, apierr.CodeValidation, "token is required"))
+		return
+	}
+
+	err := h.verification.VerifyEmail(r.Context(), token)
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		apierr.WriteError(w, apierr.New(http.StatusNotFound, apierr.CodeNotFound, "Invalid verification token"))
+		return
+	case errors.Is(err, service.ErrTokenExpired):
+		apierr.WriteError(w, apierr.New(http.StatusGone, apierr.CodeValidation, "Verification token has expired"))
+		return
+	case err != nil:
+		log.Printf("VerifyEmail error: %v", err)
+		apierr.WriteError(w, apierr.New(http.StatusInternalServerError, apierr.CodeInternal, "Internal server error"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ksx2/trpo3_code_style/auth"
+	"ksx2/trpo3_code_style/mail"
+	"ksx2/trpo3_code_style/repository"
+	"ksx2/trpo3_code_style/service"
+)
+
+func TestVerifyEmailAllowsLogin(t *testing.T) {
+	t.Setenv("JWT_SIGNING_SECRET", "test-signing-secret")
+
+	repo := repository.NewFakeUserRepo()
+	tokens := repository.NewFakeVerificationTokenRepo()
+	hasher := auth.NewHasher(4)
+	svc := service.NewUserService(repo, hasher)
+	verification := service.NewVerificationService(tokens, repo, mail.LogMailer{}, "http://localhost")
+	h := NewUserHandlerWithService(svc, verification, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email": "alice@example.com", "password": "hunter22", "name": "Alice", "age": 30,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	h.CreateUser(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	loginBody, _ := json.Marshal(map[string]string{"email": "alice@example.com", "password": "hunter22"})
+	loginReq := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(loginBody))
+	loginW := httptest.NewRecorder()
+	h.LoginUser(loginW, loginReq)
+	if loginW.Code != http.StatusForbidden {
+		t.Fatalf("expected login to be blocked before verification, got %d: %s", loginW.Code, loginW.Body.String())
+	}
+
+	u, err := repo.FindByEmail(req.Context(), "alice@example.com")
+	if err != nil {
+		t.Fatalf("FindByEmail: %v", err)
+	}
+	if err := repo.MarkVerified(req.Context(), u.ID); err != nil {
+		t.Fatalf("MarkVerified: %v", err)
+	}
+
+	loginReq2 := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(loginBody))
+	loginW2 := httptest.NewRecorder()
+	h.LoginUser(loginW2, loginReq2)
+	if loginW2.Code != http.StatusOK {
+		t.Fatalf("expected login to succeed after verification, got %d: %s", loginW2.Code, loginW2.Body.String())
+	}
+}
+
+func TestResendVerificationRejectsOtherAccounts(t *testing.T) {
+	t.Setenv("JWT_SIGNING_SECRET", "test-signing-secret")
+	h := newTestHandler()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"email": "alice@example.com", "password": "hunter22", "name": "Alice", "age": 30,
+	})
+	createReq := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	createW := httptest.NewRecorder()
+	h.CreateUser(createW, createReq)
+	if createW.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", createW.Code, createW.Body.String())
+	}
+
+	attackerToken, err := auth.IssueAccessToken(999)
+	if err != nil {
+		t.Fatalf("IssueAccessToken: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1/verify/resend", nil)
+	req.Header.Set("Authorization", "Bearer "+attackerToken)
+	w := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 when resending verification for another account, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestResendVerificationRequiresAuth(t *testing.T) {
+	h := newTestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1/verify/resend", nil)
+	w := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unauthenticated resend request, got %d: %s", w.Code, w.Body.String())
+	}
+}